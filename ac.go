@@ -0,0 +1,186 @@
+package trie
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Match describes a single occurrence of an inserted word found while
+// scanning input text with FindAll or Scan.
+type Match struct {
+	Word       string
+	Start, End int
+}
+
+// Compile builds the Aho-Corasick automaton on top of the current
+// contents of the tree: a BFS from the root sets, for every node n
+// reached from its parent p by rune r, a fail link to the deepest
+// proper suffix of n that is also a path in the trie, along with a
+// dictLink to the nearest fail-ancestor that terminates a word. This
+// is what lets FindAll/Scan report every match in a single pass over
+// the input instead of calling Contains per substring.
+//
+// The automaton reflects the words in the tree at the time Compile
+// runs. Add, Delete and DeleteSubtree mark it stale, and FindAll and
+// Scan call Compile automatically if the tree has changed since the
+// last Compile.
+func (t Tree) Compile() {
+	t.root.fail = nil
+	t.root.depth = 0
+	t.root.maxDepth = 0
+
+	queue := make([]*trieNode, 0, len(t.root.children))
+	for r, child := range t.root.children {
+		if r == t.specialEndRune {
+			continue
+		}
+
+		child.fail = t.root
+		child.depth = 1
+		t.root.maxDepth = 1
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		if _, ok := n.fail.children[t.specialEndRune]; ok {
+			n.dictLink = n.fail
+		} else {
+			n.dictLink = n.fail.dictLink
+		}
+
+		for r, child := range n.children {
+			if r == t.specialEndRune {
+				continue
+			}
+
+			f := n.fail
+			for f != nil && f.children[r] == nil {
+				f = f.fail
+			}
+
+			if f == nil {
+				child.fail = t.root
+			} else {
+				child.fail = f.children[r]
+			}
+
+			child.depth = n.depth + 1
+			if child.depth > t.root.maxDepth {
+				t.root.maxDepth = child.depth
+			}
+			queue = append(queue, child)
+		}
+	}
+
+	t.root.compiled = true
+}
+
+// FindAll reports every occurrence of any word added to the tree
+// within text, including overlapping matches. It is equivalent to,
+// but far faster than, calling Contains on every substring of text.
+func (t Tree) FindAll(text string) []Match {
+	var matches []Match
+	t.Scan(strings.NewReader(text), func(m Match) bool {
+		matches = append(matches, m)
+		return true
+	})
+
+	return matches
+}
+
+// Scan streams runes out of r, calling fn with every Match found as
+// soon as it is found. Scanning stops early if fn returns false.
+// Start and End are byte offsets into the stream consumed from r.
+//
+// Scan only ever needs to look back as far as the tree's longest
+// word to report a match, so it keeps the trailing maxDepth runes of
+// history in a fixed-size ring buffer rather than the whole stream:
+// memory use is bounded by the dictionary, not by the size of r.
+func (t Tree) Scan(r io.Reader, fn func(Match) bool) error {
+	if !t.root.compiled {
+		t.Compile()
+	}
+
+	maxDepth := t.root.maxDepth
+
+	var starts []int
+	var runes []rune
+	if maxDepth > 0 {
+		starts = make([]int, maxDepth)
+		runes = make([]rune, maxDepth)
+	}
+
+	br := bufio.NewReader(r)
+	cur := t.root
+	offset := 0
+	count := 0
+
+	for {
+		c, size, err := br.ReadRune()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if maxDepth > 0 {
+			idx := count % maxDepth
+			starts[idx] = offset
+			runes[idx] = c
+		}
+		offset += size
+		count++
+
+		for cur != t.root && cur.children[c] == nil {
+			cur = cur.fail
+		}
+
+		if next, ok := cur.children[c]; ok && next != nil {
+			cur = next
+		}
+
+		if _, ok := cur.children[t.specialEndRune]; ok {
+			if !t.emit(cur, starts, runes, count, offset, fn) {
+				return nil
+			}
+		} else if cur.dictLink != nil {
+			if !t.emit(cur.dictLink, starts, runes, count, offset, fn) {
+				return nil
+			}
+		}
+	}
+}
+
+// emit walks the dictLink chain starting at n, calling fn for every
+// word it terminates. starts and runes are the ring buffers Scan
+// fills as it reads, count is the total number of runes read so far,
+// and end is the current byte offset. It returns false as soon as fn
+// asks to stop.
+func (t Tree) emit(n *trieNode, starts []int, runes []rune, count, end int, fn func(Match) bool) bool {
+	size := len(runes)
+
+	for n != nil {
+		word := make([]rune, n.depth)
+		start := 0
+		for i := 0; i < n.depth; i++ {
+			idx := (count - n.depth + i) % size
+			word[i] = runes[idx]
+			if i == 0 {
+				start = starts[idx]
+			}
+		}
+
+		if !fn(Match{Word: string(word), Start: start, End: end}) {
+			return false
+		}
+
+		n = n.dictLink
+	}
+
+	return true
+}
@@ -0,0 +1,140 @@
+package trie
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestFindAll(t *testing.T) {
+	tt := NewTrieTree('*')
+	for _, word := range []string{"he", "she", "his", "hers"} {
+		tt.Add(word)
+	}
+
+	matches := tt.FindAll("ushers")
+
+	want := []Match{
+		{Word: "she", Start: 1, End: 4},
+		{Word: "he", Start: 2, End: 4},
+		{Word: "hers", Start: 2, End: 6},
+	}
+
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("FindAll Error: Expected: %v, Actual: %v", want, matches)
+	}
+}
+
+func TestFindAllNoMatches(t *testing.T) {
+	tt := NewTrieTree('*')
+	tt.Add("golang")
+
+	if matches := tt.FindAll("no words from the dictionary here"); len(matches) != 0 {
+		t.Errorf("FindAll Error: Expected no matches, Actual: %v", matches)
+	}
+}
+
+func TestFindAllRecompilesAfterAdd(t *testing.T) {
+	tt := NewTrieTree('*')
+	tt.Add("cat")
+
+	if matches := tt.FindAll("the cat sat"); len(matches) != 1 {
+		t.Errorf("FindAll Error: Expected 1 match, Actual: %v", matches)
+	}
+
+	tt.Add("sat")
+
+	matches := tt.FindAll("the cat sat")
+	if len(matches) != 2 {
+		t.Errorf("FindAll Error: Expected 2 matches after adding a new word, Actual: %v", matches)
+	}
+}
+
+func TestScanFindsMatchFarPastBufferedHistory(t *testing.T) {
+	tt := NewTrieTree('*')
+	tt.Add("needle")
+
+	text := strings.Repeat("x", 100000) + "needle" + strings.Repeat("x", 100000)
+
+	matches := tt.FindAll(text)
+	if len(matches) != 1 || matches[0].Word != "needle" {
+		t.Fatalf("FindAll Error: Expected 1 match for \"needle\", Actual: %v", matches)
+	}
+}
+
+func TestScanMemoryIsBoundedByDictionaryNotInput(t *testing.T) {
+	tt := NewTrieTree('*')
+	tt.Add("needle")
+
+	allocsFor := func(n int) uint64 {
+		text := strings.Repeat("haystack ", n)
+
+		runtime.GC()
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+
+		tt.Scan(strings.NewReader(text), func(m Match) bool { return true })
+
+		runtime.ReadMemStats(&after)
+		return after.TotalAlloc - before.TotalAlloc
+	}
+
+	small := allocsFor(10000)
+	large := allocsFor(100000)
+
+	// The input is 10x bigger; if Scan buffered the whole stream
+	// instead of a bounded window, allocations would scale with it.
+	// Bounded buffering should keep the larger run well under that.
+	if large > small*3 {
+		t.Errorf("Scan allocations grew with input size, Expected: roughly flat, Actual: %d -> %d bytes", small, large)
+	}
+}
+
+func TestScanMemoryIsBoundedForEmptyTree(t *testing.T) {
+	tt := NewTrieTree('*')
+	tt.Compile()
+
+	allocsFor := func(n int) uint64 {
+		text := strings.Repeat("y", n)
+
+		runtime.GC()
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+
+		tt.Scan(strings.NewReader(text), func(m Match) bool { return true })
+
+		runtime.ReadMemStats(&after)
+		return after.TotalAlloc - before.TotalAlloc
+	}
+
+	small := allocsFor(50000)
+	large := allocsFor(500000)
+
+	// A tree with no words at all has a maxDepth of 0: Scan must not
+	// fall back to an unbounded buffer in that case, since no word
+	// can ever match regardless of how much history is kept.
+	if large > small*3 {
+		t.Errorf("Scan allocations grew with input size on an empty tree, Expected: roughly flat, Actual: %d -> %d bytes", small, large)
+	}
+}
+
+func TestScanStopsEarly(t *testing.T) {
+	tt := NewTrieTree('*')
+	for _, word := range []string{"he", "she", "his", "hers"} {
+		tt.Add(word)
+	}
+
+	var got []Match
+	err := tt.Scan(strings.NewReader("ushers"), func(m Match) bool {
+		got = append(got, m)
+		return len(got) < 1
+	})
+
+	if err != nil {
+		t.Errorf("Scan Error: Expected no error, Actual: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("Scan Error: Expected scanning to stop after 1 match, Actual: %v", got)
+	}
+}
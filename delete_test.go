@@ -0,0 +1,92 @@
+package trie
+
+import "testing"
+
+func TestDeleteReportsPresence(t *testing.T) {
+	tt := NewTrieTree('*')
+	tt.Add("robert")
+
+	if tt.Delete("nope") {
+		t.Error("Delete should return false for a word that was never added")
+	}
+	if !tt.Delete("robert") {
+		t.Error("Delete should return true for a word that was added")
+	}
+	if tt.Delete("robert") {
+		t.Error("Delete should return false the second time the same word is deleted")
+	}
+	if tt.Contains("robert") {
+		t.Error("Tree should not contain a deleted word")
+	}
+}
+
+func TestDeletePrunesDeadNodes(t *testing.T) {
+	tt := NewTrieTree('*')
+	tt.Add("cat")
+
+	tt.Delete("cat")
+
+	if len(tt.root.children) != 0 {
+		t.Errorf("Delete should have pruned every node along the path, Actual root.children: %v", tt.root.children)
+	}
+}
+
+func TestDeleteKeepsSharedPrefixes(t *testing.T) {
+	tt := NewTrieTree('*')
+	tt.Add("car")
+	tt.Add("cart")
+	tt.Add("ca")
+
+	if !tt.Delete("car") {
+		t.Error("Delete should return true for \"car\"")
+	}
+
+	if tt.Contains("car") {
+		t.Error("Tree should not contain \"car\" after it is deleted")
+	}
+	if !tt.Contains("cart") {
+		t.Error("Deleting \"car\" should not remove \"cart\"")
+	}
+	if !tt.Contains("ca") {
+		t.Error("Deleting \"car\" should not remove \"ca\"")
+	}
+}
+
+func TestDeleteSubtree(t *testing.T) {
+	tt := NewTrieTree('*')
+	for _, word := range []string{"car", "cart", "care", "cat", "dog"} {
+		tt.Add(word)
+	}
+
+	removed := tt.DeleteSubtree("ca")
+	if removed != 4 {
+		t.Errorf("DeleteSubtree Error: Expected 4 words removed, Actual: %d", removed)
+	}
+
+	for _, word := range []string{"car", "cart", "care", "cat"} {
+		if tt.Contains(word) {
+			t.Errorf("Tree should not contain %s after DeleteSubtree(\"ca\")", word)
+		}
+	}
+	if !tt.Contains("dog") {
+		t.Error("DeleteSubtree(\"ca\") should not remove \"dog\"")
+	}
+
+	if removed := tt.DeleteSubtree("zzz"); removed != 0 {
+		t.Errorf("DeleteSubtree Error: Expected 0 for unknown prefix, Actual: %d", removed)
+	}
+}
+
+func TestDeleteSubtreeEmptyPrefix(t *testing.T) {
+	tt := NewTrieTree('*')
+	for _, word := range []string{"a", "b", "c"} {
+		tt.Add(word)
+	}
+
+	if removed := tt.DeleteSubtree(""); removed != 3 {
+		t.Errorf("DeleteSubtree Error: Expected 3 words removed, Actual: %d", removed)
+	}
+	if len(tt.AllWords()) != 0 {
+		t.Error("DeleteSubtree(\"\") should remove every word")
+	}
+}
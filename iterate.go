@@ -0,0 +1,68 @@
+package trie
+
+import "sort"
+
+// Iterate walks every word stored under prefix, in sorted rune order,
+// calling fn with each one. Iteration stops early if fn returns false.
+// If prefix itself isn't a path in the tree, Iterate calls fn zero
+// times.
+func (t Tree) Iterate(prefix string, fn func(word string) bool) {
+	curNode := t.root
+	for _, c := range prefix {
+		next, ok := curNode.children[c]
+		if !ok || next == nil {
+			return
+		}
+
+		curNode = next
+	}
+
+	t.iterate(curNode, []rune(prefix), fn)
+}
+
+// iterate is the recursive DFS behind Iterate: it emits the word for
+// node (if node terminates one) and then descends into node's
+// children in sorted rune order, returning false as soon as fn asks
+// to stop.
+func (t Tree) iterate(node *trieNode, path []rune, fn func(word string) bool) bool {
+	if _, ok := node.children[t.specialEndRune]; ok {
+		if !fn(string(path)) {
+			return false
+		}
+	}
+
+	runes := make([]rune, 0, len(node.children))
+	for r := range node.children {
+		if r == t.specialEndRune {
+			continue
+		}
+
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+	for _, r := range runes {
+		if !t.iterate(node.children[r], append(path, r), fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WordsWithPrefix returns every word in the tree that starts with
+// prefix, in sorted order.
+func (t Tree) WordsWithPrefix(prefix string) []string {
+	var words []string
+	t.Iterate(prefix, func(word string) bool {
+		words = append(words, word)
+		return true
+	})
+
+	return words
+}
+
+// AllWords returns every word stored in the tree, in sorted order.
+func (t Tree) AllWords() []string {
+	return t.WordsWithPrefix("")
+}
@@ -0,0 +1,54 @@
+package trie
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWordsWithPrefix(t *testing.T) {
+	tt := NewTrieTree('*')
+	for _, word := range []string{"car", "cart", "care", "cat", "dog"} {
+		tt.Add(word)
+	}
+
+	got := tt.WordsWithPrefix("ca")
+	want := []string{"car", "care", "cart", "cat"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WordsWithPrefix Error: Expected: %v, Actual: %v", want, got)
+	}
+
+	if got := tt.WordsWithPrefix("zzz"); got != nil {
+		t.Errorf("WordsWithPrefix Error: Expected nil for unknown prefix, Actual: %v", got)
+	}
+}
+
+func TestAllWords(t *testing.T) {
+	tt := NewTrieTree('*')
+	words := []string{"banana", "apple", "cherry"}
+	for _, word := range words {
+		tt.Add(word)
+	}
+
+	got := tt.AllWords()
+	want := []string{"apple", "banana", "cherry"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AllWords Error: Expected: %v, Actual: %v", want, got)
+	}
+}
+
+func TestIterateStopsEarly(t *testing.T) {
+	tt := NewTrieTree('*')
+	for _, word := range []string{"a", "b", "c"} {
+		tt.Add(word)
+	}
+
+	var seen []string
+	tt.Iterate("", func(word string) bool {
+		seen = append(seen, word)
+		return false
+	})
+
+	if len(seen) != 1 || seen[0] != "a" {
+		t.Errorf("Iterate Error: Expected to stop after first word \"a\", Actual: %v", seen)
+	}
+}
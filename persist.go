@@ -0,0 +1,474 @@
+package trie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrNodeNotFound is returned by a Backend's Get when the requested
+// key has no value, so the Cache knows to treat it as a missing node
+// rather than propagating a lower-level I/O error.
+var ErrNodeNotFound = errors.New("trie: node not found")
+
+// Backend is a pluggable key/value store that a Cache flushes trie
+// nodes to and lazily loads them back from. Implementations are
+// expected to wrap something like BoltDB, LevelDB, or BadgerDB; see
+// MapBackend for a trivial in-memory implementation.
+type Backend interface {
+	Get(key []byte) ([]byte, error)
+	Put(key, value []byte) error
+	Delete(key []byte) error
+}
+
+// MapBackend is a Backend backed by an in-memory map. It is mostly
+// useful for tests and for callers who want the Cache/Sync/Undo
+// bookkeeping without an actual on-disk store.
+type MapBackend struct {
+	values map[string][]byte
+}
+
+// NewMapBackend creates an empty MapBackend.
+func NewMapBackend() *MapBackend {
+	return &MapBackend{values: make(map[string][]byte)}
+}
+
+// Get returns the value stored for key, or ErrNodeNotFound if there
+// isn't one.
+func (m *MapBackend) Get(key []byte) ([]byte, error) {
+	v, ok := m.values[string(key)]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+
+	return v, nil
+}
+
+// Put stores value under key, overwriting any previous value.
+func (m *MapBackend) Put(key, value []byte) error {
+	m.values[string(key)] = value
+	return nil
+}
+
+// Delete removes key, if present.
+func (m *MapBackend) Delete(key []byte) error {
+	delete(m.values, string(key))
+	return nil
+}
+
+// persistNode is the on-disk/in-memory representation of a trie node
+// in a PersistentTree: rather than a pointer, children are addressed
+// by a stable node ID so a node can be serialized independently of
+// the rest of the tree and loaded back lazily.
+type persistNode struct {
+	id       uint64
+	val      rune
+	clean    bool
+	children map[rune]uint64
+}
+
+// Cache sits between a PersistentTree and a Backend. Nodes created or
+// modified by Tree operations live only in the cache until Sync
+// flushes them to the backend; nodes not yet seen this session are
+// loaded from the backend on first traversal.
+//
+// Every node ID the cache allocates is namespaced to one logical
+// tree: the top 32 bits are the tree's namespace and the bottom 32
+// bits are a per-tree counter. This is what lets more than one
+// PersistentTree share the same Backend without their Sync calls
+// overwriting each other's nodes under colliding keys.
+type Cache struct {
+	backend   Backend
+	nodes     map[uint64]*persistNode
+	dirty     map[uint64]bool
+	namespace uint64
+	nextID    uint64
+
+	// undoLog holds the inverse of every mutation applied since the
+	// last Sync, in order, so Undo can roll an arbitrary batch of
+	// uncommitted mutations back without touching the backend.
+	undoLog []func()
+}
+
+// newCache creates an empty Cache over the given backend, allocating
+// node IDs under the given namespace.
+func newCache(backend Backend, namespace uint64) *Cache {
+	return &Cache{
+		backend:   backend,
+		nodes:     make(map[uint64]*persistNode),
+		dirty:     make(map[uint64]bool),
+		namespace: namespace,
+		nextID:    namespace << 32,
+	}
+}
+
+// record appends undo to the cache's undo log.
+func (c *Cache) record(undo func()) {
+	c.undoLog = append(c.undoLog, undo)
+}
+
+// mark marks a node dirty, so Sync knows to flush it.
+func (c *Cache) mark(id uint64) {
+	c.dirty[id] = true
+}
+
+// newNode allocates a fresh node with a new stable ID, marks it dirty
+// and records its own removal as the undo for its creation.
+func (c *Cache) newNode(val rune, clean bool) uint64 {
+	c.nextID++
+	id := c.nextID
+
+	c.nodes[id] = &persistNode{id: id, val: val, clean: clean, children: make(map[rune]uint64)}
+	c.mark(id)
+	c.record(func() {
+		delete(c.nodes, id)
+		delete(c.dirty, id)
+	})
+
+	return id
+}
+
+// get returns the node for id, loading and caching it from the
+// backend if this is the first time it has been seen.
+func (c *Cache) get(id uint64) (*persistNode, error) {
+	if n, ok := c.nodes[id]; ok {
+		return n, nil
+	}
+
+	data, err := c.backend.Get(encodeNodeID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := decodeNode(id, data)
+	if err != nil {
+		return nil, err
+	}
+
+	c.nodes[id] = n
+	return n, nil
+}
+
+// Sync flushes every dirty node to the backend, along with this
+// tree's node ID counter so a later Open knows where to resume
+// allocating IDs from, and clears the undo log: after Sync, Undo has
+// nothing left to roll back.
+func (c *Cache) Sync() error {
+	for id := range c.dirty {
+		data, err := encodeNode(c.nodes[id])
+		if err != nil {
+			return err
+		}
+
+		if err := c.backend.Put(encodeNodeID(id), data); err != nil {
+			return err
+		}
+	}
+
+	if err := c.backend.Put(nodeCounterKey(c.namespace), encodeNodeID(c.nextID)); err != nil {
+		return err
+	}
+
+	c.dirty = make(map[uint64]bool)
+	c.undoLog = nil
+	return nil
+}
+
+// Undo reverts every mutation recorded since the last Sync (or since
+// the Cache was created, if Sync has never been called).
+func (c *Cache) Undo() {
+	for i := len(c.undoLog) - 1; i >= 0; i-- {
+		c.undoLog[i]()
+	}
+
+	c.undoLog = nil
+}
+
+// Evict drops every clean (already-Sync'd) node out of memory, so
+// the next traversal that needs it reloads it from the backend. It
+// never drops a dirty node, so it's always safe to call, but it's
+// most useful right after Sync: without an Evict step, every node a
+// tree has ever touched stays resident for the life of the Cache,
+// which defeats the point of backing a trie larger than RAM.
+func (c *Cache) Evict() {
+	for id := range c.nodes {
+		if !c.dirty[id] {
+			delete(c.nodes, id)
+		}
+	}
+}
+
+// encodeNodeID turns a node ID into the key a Backend stores it
+// under.
+func encodeNodeID(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
+
+// nodeCounterKey is the key a Backend stores a tree's node ID
+// counter under. It is always longer than the 8 bytes encodeNodeID
+// produces, so it can never collide with a real node's key.
+func nodeCounterKey(namespace uint64) []byte {
+	key := append([]byte("trie:node-counter:"), encodeNodeID(namespace)...)
+	return key
+}
+
+// encodeNode serializes a node as its rune, clean bit, and child ID
+// map so it can be stored in a Backend and decoded back by
+// decodeNode.
+func encodeNode(n *persistNode) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := binary.Write(&buf, binary.BigEndian, n.val); err != nil {
+		return nil, err
+	}
+
+	clean := byte(0)
+	if n.clean {
+		clean = 1
+	}
+	buf.WriteByte(clean)
+
+	if err := binary.Write(&buf, binary.BigEndian, uint32(len(n.children))); err != nil {
+		return nil, err
+	}
+
+	for r, childID := range n.children {
+		if err := binary.Write(&buf, binary.BigEndian, r); err != nil {
+			return nil, err
+		}
+		if err := binary.Write(&buf, binary.BigEndian, childID); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeNode is the inverse of encodeNode for the node identified by
+// id.
+func decodeNode(id uint64, data []byte) (*persistNode, error) {
+	buf := bytes.NewReader(data)
+	n := &persistNode{id: id}
+
+	if err := binary.Read(buf, binary.BigEndian, &n.val); err != nil {
+		return nil, err
+	}
+
+	clean, err := buf.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	n.clean = clean == 1
+
+	var childCount uint32
+	if err := binary.Read(buf, binary.BigEndian, &childCount); err != nil {
+		return nil, err
+	}
+
+	n.children = make(map[rune]uint64, childCount)
+	for i := uint32(0); i < childCount; i++ {
+		var r rune
+		var childID uint64
+		if err := binary.Read(buf, binary.BigEndian, &r); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(buf, binary.BigEndian, &childID); err != nil {
+			return nil, err
+		}
+		n.children[r] = childID
+	}
+
+	return n, nil
+}
+
+// PersistentTree is a trie tree whose nodes are backed by a Backend:
+// it behaves like Tree, but newly added nodes only live in memory
+// until Sync is called, and nodes from a previously persisted tree
+// are loaded back lazily as they're traversed.
+type PersistentTree struct {
+	root           uint64
+	specialEndRune rune
+	cache          *Cache
+}
+
+// NewPersistentTrieTree creates a new PersistentTree over backend
+// that keeps the given specialEndRune as a special character that
+// cannot be used in words given to the tree.
+//
+// namespace must be unique among every PersistentTree sharing
+// backend: node IDs are only unique within a namespace, so two trees
+// created with the same namespace over the same backend will
+// overwrite each other's nodes as soon as both Sync. Callers with
+// only one tree per backend can simply pass 0.
+func NewPersistentTrieTree(backend Backend, namespace uint64, specialEndRune rune) *PersistentTree {
+	cache := newCache(backend, namespace)
+	root := cache.newNode(specialEndRune, true)
+
+	// The root's own creation must never be undoable through the
+	// public API: Undo replays the whole log, so without this an
+	// Undo call on a tree that's never been Sync'd would delete the
+	// root itself and brick the tree for good.
+	cache.undoLog = nil
+
+	return &PersistentTree{root: root, specialEndRune: specialEndRune, cache: cache}
+}
+
+// Open reconnects to a PersistentTree that was previously Sync'd to
+// backend, picking up its node ID counter where Sync left off so
+// newly added nodes can't collide with ones already there. rootID is
+// the value RootID returned for the tree when it was created, and
+// specialEndRune must match the value the tree was originally
+// created with.
+func Open(backend Backend, rootID uint64, specialEndRune rune) (*PersistentTree, error) {
+	cache := newCache(backend, rootID>>32)
+
+	if data, err := backend.Get(nodeCounterKey(cache.namespace)); err == nil {
+		cache.nextID = binary.BigEndian.Uint64(data)
+	} else if !errors.Is(err, ErrNodeNotFound) {
+		return nil, err
+	}
+
+	if _, err := cache.get(rootID); err != nil {
+		return nil, fmt.Errorf("trie: opening root %d: %w", rootID, err)
+	}
+
+	return &PersistentTree{root: rootID, specialEndRune: specialEndRune, cache: cache}, nil
+}
+
+// RootID returns the stable node ID of the tree's root, which Open
+// needs to reconnect to this tree later.
+func (t *PersistentTree) RootID() uint64 {
+	return t.root
+}
+
+// Evict drops every already-synced node out of memory; see
+// Cache.Evict.
+func (t *PersistentTree) Evict() {
+	t.cache.Evict()
+}
+
+// Add adds the given word to the tree, creating nodes in the cache as
+// needed. The new nodes are not written to the backend until Sync is
+// called. Add returns false, and rolls back any nodes it created,
+// if word contains the tree's special end rune or backend access
+// fails while walking an existing path.
+func (t *PersistentTree) Add(word string) bool {
+	if len(word) == 0 {
+		return true
+	}
+
+	mark := len(t.cache.undoLog)
+	curID := t.root
+
+	for _, c := range word {
+		if c == t.specialEndRune {
+			t.rollbackTo(mark)
+			return false
+		}
+
+		node, err := t.cache.get(curID)
+		if err != nil {
+			t.rollbackTo(mark)
+			return false
+		}
+
+		childID, ok := node.children[c]
+		if !ok {
+			parentWasDirty := t.cache.dirty[curID]
+
+			childID = t.cache.newNode(c, false)
+			node.children[c] = childID
+			t.cache.mark(curID)
+
+			child, parentID := c, curID
+			t.cache.record(func() {
+				delete(node.children, child)
+				if !parentWasDirty {
+					delete(t.cache.dirty, parentID)
+				}
+			})
+		}
+
+		curID = childID
+	}
+
+	lastNode, err := t.cache.get(curID)
+	if err != nil {
+		t.rollbackTo(mark)
+		return false
+	}
+
+	if _, ok := lastNode.children[t.specialEndRune]; ok {
+		return false
+	}
+
+	lastWasDirty := t.cache.dirty[curID]
+
+	lastNode.children[t.specialEndRune] = 0
+	t.cache.mark(curID)
+
+	t.cache.record(func() {
+		delete(lastNode.children, t.specialEndRune)
+		if !lastWasDirty {
+			delete(t.cache.dirty, curID)
+		}
+	})
+
+	return true
+}
+
+// rollbackTo undoes every mutation recorded since mark, leaving
+// earlier, already-successful Adds in this batch untouched.
+func (t *PersistentTree) rollbackTo(mark int) {
+	for i := len(t.cache.undoLog) - 1; i >= mark; i-- {
+		t.cache.undoLog[i]()
+	}
+
+	t.cache.undoLog = t.cache.undoLog[:mark]
+}
+
+// Contains returns true if the given word is contained in the tree,
+// loading nodes from the backend as needed.
+func (t *PersistentTree) Contains(word string) bool {
+	if len(word) == 0 {
+		return true
+	}
+
+	curID := t.root
+	for _, c := range word {
+		node, err := t.cache.get(curID)
+		if err != nil {
+			return false
+		}
+
+		childID, ok := node.children[c]
+		if !ok {
+			return false
+		}
+
+		curID = childID
+	}
+
+	node, err := t.cache.get(curID)
+	if err != nil {
+		return false
+	}
+
+	_, ok := node.children[t.specialEndRune]
+	return ok
+}
+
+// Sync flushes every node created or modified since the last Sync to
+// the backend.
+func (t *PersistentTree) Sync() error {
+	return t.cache.Sync()
+}
+
+// Undo reverts every Add made since the last Sync (or since the tree
+// was created, if Sync has never been called).
+func (t *PersistentTree) Undo() {
+	t.cache.Undo()
+}
@@ -0,0 +1,201 @@
+package trie
+
+import "testing"
+
+func TestPersistentTreeAddContains(t *testing.T) {
+	backend := NewMapBackend()
+	pt := NewPersistentTrieTree(backend, 0, '*')
+
+	words := []string{"robert", "bad", "wold"}
+	for _, word := range words {
+		if !pt.Add(word) {
+			t.Errorf("Add returned false for word %s", word)
+		}
+	}
+
+	for _, word := range words {
+		if !pt.Contains(word) {
+			t.Errorf("Tree should contain word %s", word)
+		}
+	}
+
+	if pt.Contains("missing") {
+		t.Error("Tree should not contain word that was never added")
+	}
+
+	// Re-adding a word should fail, same as Tree.Add.
+	if pt.Add("bad") {
+		t.Error("Add should return false for a word already in the tree")
+	}
+
+	// A word containing the special end rune should fail and leave
+	// no partial nodes behind.
+	if pt.Add("te*st") {
+		t.Error("Add should return false for a word with the special end rune in it")
+	}
+	if pt.Contains("te") {
+		t.Error("Add should not have left a partial node behind for an invalid word")
+	}
+}
+
+func TestPersistentTreeSyncAndOpen(t *testing.T) {
+	backend := NewMapBackend()
+	pt := NewPersistentTrieTree(backend, 0, '*')
+
+	for _, word := range []string{"golang", "gopher"} {
+		pt.Add(word)
+	}
+
+	if err := pt.Sync(); err != nil {
+		t.Fatalf("Sync returned an error: %v", err)
+	}
+
+	reloaded, err := Open(backend, pt.RootID(), '*')
+	if err != nil {
+		t.Fatalf("Open returned an error: %v", err)
+	}
+
+	for _, word := range []string{"golang", "gopher"} {
+		if !reloaded.Contains(word) {
+			t.Errorf("reloaded tree should contain word %s after Sync", word)
+		}
+	}
+
+	// Words added after reopening must not collide with IDs already
+	// used by the synced tree.
+	if !reloaded.Add("gopherspace") {
+		t.Error("Add should succeed for a new word on a reopened tree")
+	}
+	if !reloaded.Contains("gopherspace") || !reloaded.Contains("golang") {
+		t.Error("reloaded tree should contain both old and newly added words")
+	}
+}
+
+func TestPersistentTreeOpenRejectsUnknownRoot(t *testing.T) {
+	backend := NewMapBackend()
+	if _, err := Open(backend, 12345, '*'); err == nil {
+		t.Error("Open should return an error for a root ID that was never Sync'd")
+	}
+}
+
+func TestPersistentTreeNamespacesDontCollide(t *testing.T) {
+	backend := NewMapBackend()
+
+	a := NewPersistentTrieTree(backend, 1, '*')
+	b := NewPersistentTrieTree(backend, 2, '*')
+
+	a.Add("apple")
+	b.Add("banana")
+
+	if err := a.Sync(); err != nil {
+		t.Fatalf("Sync returned an error: %v", err)
+	}
+	if err := b.Sync(); err != nil {
+		t.Fatalf("Sync returned an error: %v", err)
+	}
+
+	reopenedA, err := Open(backend, a.RootID(), '*')
+	if err != nil {
+		t.Fatalf("Open returned an error: %v", err)
+	}
+	reopenedB, err := Open(backend, b.RootID(), '*')
+	if err != nil {
+		t.Fatalf("Open returned an error: %v", err)
+	}
+
+	if !reopenedA.Contains("apple") || reopenedA.Contains("banana") {
+		t.Error("tree a should contain only its own words after both trees Sync to the same backend")
+	}
+	if !reopenedB.Contains("banana") || reopenedB.Contains("apple") {
+		t.Error("tree b should contain only its own words after both trees Sync to the same backend")
+	}
+}
+
+func TestPersistentTreeEvict(t *testing.T) {
+	backend := NewMapBackend()
+	pt := NewPersistentTrieTree(backend, 0, '*')
+	pt.Add("golang")
+
+	if err := pt.Sync(); err != nil {
+		t.Fatalf("Sync returned an error: %v", err)
+	}
+
+	pt.Evict()
+
+	if len(pt.cache.nodes) != 0 {
+		t.Errorf("Evict should drop every synced node from memory, Actual: %d left", len(pt.cache.nodes))
+	}
+	if !pt.Contains("golang") {
+		t.Error("Tree should still find a word after Evict reloads its nodes from the backend")
+	}
+}
+
+func TestPersistentTreeUndoWithNothingSynced(t *testing.T) {
+	backend := NewMapBackend()
+	pt := NewPersistentTrieTree(backend, 0, '*')
+
+	pt.Add("word")
+	pt.Undo()
+
+	if pt.Contains("word") {
+		t.Error("Undo should have rolled back the unsynced word")
+	}
+
+	// Before the fix, Undo's replay reached all the way back to the
+	// root node's own creation and deleted it, bricking the tree.
+	if !pt.Add("other") {
+		t.Error("Add should still succeed after Undo with nothing ever Sync'd")
+	}
+	if !pt.Contains("other") {
+		t.Error("Tree should contain a word added after Undo with nothing ever Sync'd")
+	}
+}
+
+func TestPersistentTreeRollbackClearsParentDirtyFlag(t *testing.T) {
+	backend := NewMapBackend()
+	pt := NewPersistentTrieTree(backend, 0, '*')
+
+	pt.Add("ca")
+	if err := pt.Sync(); err != nil {
+		t.Fatalf("Sync returned an error: %v", err)
+	}
+	if len(pt.cache.dirty) != 0 {
+		t.Fatalf("expected nothing dirty right after Sync, Actual: %d", len(pt.cache.dirty))
+	}
+
+	// "car*s" extends the already-synced "a" node with a new child
+	// 'r' before failing because of the embedded end rune, so the
+	// 'a' node gets marked dirty and then must be unmarked again by
+	// the rollback, not left dirty for a wasted future Sync.
+	if pt.Add("car*s") {
+		t.Fatal("Add should return false for a word with the end rune in it")
+	}
+
+	if dirty := pt.cache.dirty; len(dirty) != 0 {
+		t.Errorf("rollback should leave no dirty nodes behind, Actual: %v", dirty)
+	}
+}
+
+func TestPersistentTreeUndo(t *testing.T) {
+	backend := NewMapBackend()
+	pt := NewPersistentTrieTree(backend, 0, '*')
+
+	pt.Add("committed")
+	if err := pt.Sync(); err != nil {
+		t.Fatalf("Sync returned an error: %v", err)
+	}
+
+	pt.Add("uncommitted")
+	if !pt.Contains("uncommitted") {
+		t.Fatal("Tree should contain word immediately after Add")
+	}
+
+	pt.Undo()
+
+	if pt.Contains("uncommitted") {
+		t.Error("Undo should have rolled back the uncommitted word")
+	}
+	if !pt.Contains("committed") {
+		t.Error("Undo should not roll back words already flushed by Sync")
+	}
+}
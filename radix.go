@@ -0,0 +1,197 @@
+package trie
+
+// radixNode is an internal node for a RadixTree. Unlike trieNode,
+// which stores one rune per node, a radixNode's prefix holds every
+// rune along an edge that has no branching, so chains of
+// single-child trieNodes collapse into a single radixNode.
+type radixNode struct {
+	prefix   []rune
+	isWord   bool
+	children map[rune]*radixNode
+}
+
+// newRadixNode creates a radixNode for the given edge prefix.
+func newRadixNode(prefix []rune, isWord bool) *radixNode {
+	return &radixNode{prefix: prefix, isWord: isWord, children: make(map[rune]*radixNode)}
+}
+
+// RadixTree is a PATRICIA-style compressed trie: it stores the same
+// words as a Tree, but collapses any chain of single-child nodes
+// into one edge labeled with a rune slice, which typically cuts node
+// count by an order of magnitude for large dictionaries at the cost
+// of a somewhat more involved Add.
+type RadixTree struct {
+	root    *radixNode
+	endRune rune
+}
+
+// NewRadixTree creates a new RadixTree that keeps the given endRune
+// as a special character that cannot be used in words given to the
+// tree, mirroring NewTrieTree.
+func NewRadixTree(endRune rune) *RadixTree {
+	return &RadixTree{root: newRadixNode(nil, false), endRune: endRune}
+}
+
+// Add adds the given word to the tree, splitting an edge when word
+// diverges from it mid-prefix. It returns false if word contains the
+// tree's end rune or was already present.
+func (rt *RadixTree) Add(word string) bool {
+	if len(word) == 0 {
+		return true
+	}
+
+	runes := make([]rune, 0, len(word))
+	for _, c := range word {
+		if c == rt.endRune {
+			return false
+		}
+
+		runes = append(runes, c)
+	}
+
+	return rt.add(rt.root, runes)
+}
+
+// add inserts runes under n, splitting an existing edge if runes
+// diverges from it partway through.
+func (rt *RadixTree) add(n *radixNode, runes []rune) bool {
+	if len(runes) == 0 {
+		if n.isWord {
+			return false
+		}
+
+		n.isWord = true
+		return true
+	}
+
+	child, ok := n.children[runes[0]]
+	if !ok {
+		n.children[runes[0]] = newRadixNode(runes, true)
+		return true
+	}
+
+	common := commonPrefixLen(child.prefix, runes)
+	if common == len(child.prefix) {
+		return rt.add(child, runes[common:])
+	}
+
+	// runes diverges from child.prefix partway through, so split the
+	// edge: a new mid node takes the shared prefix, with child (now
+	// holding only its remaining prefix) as one branch.
+	mid := newRadixNode(child.prefix[:common], false)
+	child.prefix = child.prefix[common:]
+	mid.children[child.prefix[0]] = child
+	n.children[runes[0]] = mid
+
+	rest := runes[common:]
+	if len(rest) == 0 {
+		mid.isWord = true
+		return true
+	}
+
+	mid.children[rest[0]] = newRadixNode(rest, true)
+	return true
+}
+
+// Contains returns true if the given word is contained in the tree.
+func (rt *RadixTree) Contains(word string) bool {
+	if len(word) == 0 {
+		return true
+	}
+
+	n, rest := rt.walk([]rune(word))
+	return n != nil && len(rest) == 0 && n.isWord
+}
+
+// Delete removes the given word from the tree, merging any edge left
+// with a single, non-word-terminating child back into its parent, and
+// reports whether the word was actually present.
+func (rt *RadixTree) Delete(word string) bool {
+	if len(word) == 0 {
+		return false
+	}
+
+	return rt.delete(rt.root, []rune(word))
+}
+
+// delete removes runes from under n, collapsing n's child back into
+// a single edge (or pruning it entirely) if the deletion left it
+// without a reason to exist as a separate node.
+func (rt *RadixTree) delete(n *radixNode, runes []rune) bool {
+	child, ok := n.children[runes[0]]
+	if !ok || len(runes) < len(child.prefix) {
+		return false
+	}
+
+	if commonPrefixLen(child.prefix, runes) != len(child.prefix) {
+		return false
+	}
+
+	rest := runes[len(child.prefix):]
+
+	var removed bool
+	if len(rest) == 0 {
+		removed = child.isWord
+		child.isWord = false
+	} else {
+		removed = rt.delete(child, rest)
+	}
+
+	if removed {
+		collapseRadixChild(n, runes[0], child)
+	}
+
+	return removed
+}
+
+// collapseRadixChild removes child (keyed by r in parent.children) if
+// it no longer needs to exist on its own: a childless, non-word node
+// is pruned outright, and a non-word node with exactly one child is
+// merged into a single edge with that child.
+func collapseRadixChild(parent *radixNode, r rune, child *radixNode) {
+	if child.isWord {
+		return
+	}
+
+	switch len(child.children) {
+	case 0:
+		delete(parent.children, r)
+	case 1:
+		for _, grandchild := range child.children {
+			grandchild.prefix = append(append([]rune{}, child.prefix...), grandchild.prefix...)
+			parent.children[r] = grandchild
+		}
+	}
+}
+
+// walk follows runes from the root as far as it can, returning the
+// last node it reached and whatever of runes wasn't consumed getting
+// there. A non-empty remainder means runes isn't a path in the tree.
+func (rt *RadixTree) walk(runes []rune) (*radixNode, []rune) {
+	n := rt.root
+	for len(runes) > 0 {
+		child, ok := n.children[runes[0]]
+		if !ok {
+			return nil, runes
+		}
+
+		if len(runes) < len(child.prefix) || commonPrefixLen(child.prefix, runes) != len(child.prefix) {
+			return nil, runes
+		}
+
+		runes = runes[len(child.prefix):]
+		n = child
+	}
+
+	return n, runes
+}
+
+// commonPrefixLen returns how many leading runes a and b share.
+func commonPrefixLen(a, b []rune) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
@@ -0,0 +1,81 @@
+package trie
+
+import "testing"
+
+func TestRadixTreeAddContains(t *testing.T) {
+	rt := NewRadixTree('*')
+
+	words := []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus"}
+	for _, word := range words {
+		if !rt.Add(word) {
+			t.Errorf("Add returned false for word %s", word)
+		}
+	}
+
+	for _, word := range words {
+		if !rt.Contains(word) {
+			t.Errorf("Tree should contain word %s", word)
+		}
+	}
+
+	for _, word := range []string{"rom", "rube", "rubicund", "other"} {
+		if rt.Contains(word) {
+			t.Errorf("Tree should not contain word %s", word)
+		}
+	}
+
+	// Re-adding a word should fail.
+	if rt.Add("romane") {
+		t.Error("Add should return false for a word already in the tree")
+	}
+
+	// A word containing the end rune should fail.
+	if rt.Add("test*word") {
+		t.Error("Add should return false for a word with the end rune in it")
+	}
+}
+
+func TestRadixTreeAddSplitsSharedPrefixWord(t *testing.T) {
+	rt := NewRadixTree('*')
+
+	rt.Add("romanus")
+	rt.Add("roman")
+
+	if !rt.Contains("romanus") || !rt.Contains("roman") {
+		t.Error("Both the original word and the shorter shared-prefix word should be present")
+	}
+	if rt.Contains("roma") {
+		t.Error("Tree should not contain a prefix that was never added as its own word")
+	}
+}
+
+func TestRadixTreeDelete(t *testing.T) {
+	rt := NewRadixTree('*')
+	for _, word := range []string{"romane", "romanus", "romulus"} {
+		rt.Add(word)
+	}
+
+	if rt.Delete("missing") {
+		t.Error("Delete should return false for a word that was never added")
+	}
+
+	if !rt.Delete("romanus") {
+		t.Error("Delete should return true for a word that was added")
+	}
+	if rt.Contains("romanus") {
+		t.Error("Tree should not contain a deleted word")
+	}
+	if !rt.Contains("romane") {
+		t.Error("Deleting \"romanus\" should not remove \"romane\"")
+	}
+	if !rt.Contains("romulus") {
+		t.Error("Deleting \"romanus\" should not remove \"romulus\"")
+	}
+
+	if !rt.Delete("romane") {
+		t.Error("Delete should return true for \"romane\"")
+	}
+	if !rt.Contains("romulus") {
+		t.Error("Tree should still contain \"romulus\" after the rest of its siblings are gone")
+	}
+}
@@ -0,0 +1,263 @@
+package trie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// binaryMagic and binaryVersion identify and version the framing used
+// by MarshalBinary/UnmarshalBinary: a header of the magic, version,
+// and specialEndRune, followed by a preorder walk of the tree
+// emitting (childCount, isTerminal, then each (rune, subtree)) per
+// node with varint-encoded runes and counts.
+var binaryMagic = []byte("TRIE")
+
+const binaryVersion = 1
+
+// MarshalBinary encodes the tree into the compact framing described
+// above, satisfying encoding.BinaryMarshaler.
+func (t Tree) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.Write(binaryMagic)
+	buf.WriteByte(binaryVersion)
+	if err := writeVarint(&buf, int64(t.specialEndRune)); err != nil {
+		return nil, err
+	}
+	if err := writeNode(&buf, t.root, t.specialEndRune); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the tree's contents with the data encoded
+// by MarshalBinary, satisfying encoding.BinaryUnmarshaler.
+func (t *Tree) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+
+	magic := make([]byte, len(binaryMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return err
+	}
+	if !bytes.Equal(magic, binaryMagic) {
+		return errors.New("trie: data is not a trie.Tree binary encoding")
+	}
+
+	version, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	if version != binaryVersion {
+		return fmt.Errorf("trie: unsupported binary encoding version %d", version)
+	}
+
+	rawEndRune, err := binary.ReadVarint(r)
+	if err != nil {
+		return err
+	}
+	specialEndRune := rune(rawEndRune)
+
+	root := newTrieNode(specialEndRune, true)
+	if err := readNode(r, root, specialEndRune); err != nil {
+		return err
+	}
+
+	t.root = root
+	t.specialEndRune = specialEndRune
+	return nil
+}
+
+// WriteTo writes the tree's MarshalBinary encoding to w, satisfying
+// io.WriterTo.
+func (t Tree) WriteTo(w io.Writer) (int64, error) {
+	data, err := t.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom replaces the tree's contents by reading a MarshalBinary
+// encoding from r, satisfying io.ReaderFrom.
+func (t *Tree) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+
+	if err := t.UnmarshalBinary(data); err != nil {
+		return int64(len(data)), err
+	}
+
+	return int64(len(data)), nil
+}
+
+// writeNode writes n's childCount and isTerminal bit, then each
+// non-end-rune child in sorted rune order as (rune, subtree).
+func writeNode(w io.Writer, n *trieNode, specialEndRune rune) error {
+	terminal := false
+	keys := make([]rune, 0, len(n.children))
+	for r := range n.children {
+		if r == specialEndRune {
+			terminal = true
+			continue
+		}
+
+		keys = append(keys, r)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	if err := writeVarint(w, int64(len(keys))); err != nil {
+		return err
+	}
+	if err := writeBool(w, terminal); err != nil {
+		return err
+	}
+
+	for _, r := range keys {
+		if err := writeVarint(w, int64(r)); err != nil {
+			return err
+		}
+		if err := writeNode(w, n.children[r], specialEndRune); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readNode is the inverse of writeNode: it populates n's children by
+// reading its childCount, isTerminal bit, and then each child.
+func readNode(r *bytes.Reader, n *trieNode, specialEndRune rune) error {
+	count, err := binary.ReadVarint(r)
+	if err != nil {
+		return err
+	}
+
+	terminal, err := readBool(r)
+	if err != nil {
+		return err
+	}
+	if terminal {
+		n.children[specialEndRune] = nil
+	}
+
+	for i := int64(0); i < count; i++ {
+		rv, err := binary.ReadVarint(r)
+		if err != nil {
+			return err
+		}
+
+		child := newTrieNode(rune(rv), true)
+		n.children[rune(rv)] = child
+		if err := readNode(r, child, specialEndRune); err != nil {
+			return err
+		}
+	}
+
+	n.clean = true
+	return nil
+}
+
+// writeVarint writes x to w as a varint.
+func writeVarint(w io.Writer, x int64) error {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutVarint(buf, x)
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+// writeBool writes b to w as a single byte.
+func writeBool(w io.Writer, b bool) error {
+	v := byte(0)
+	if b {
+		v = 1
+	}
+
+	_, err := w.Write([]byte{v})
+	return err
+}
+
+// readBool reads a single byte written by writeBool.
+func readBool(r io.Reader) (bool, error) {
+	buf := make([]byte, 1)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return false, err
+	}
+
+	return buf[0] == 1, nil
+}
+
+// jsonNode is the nested {"r":...,"children":[...]} debugging form
+// produced by MarshalJSON and consumed by UnmarshalJSON.
+type jsonNode struct {
+	R        rune       `json:"r"`
+	Word     bool       `json:"word,omitempty"`
+	Children []jsonNode `json:"children,omitempty"`
+}
+
+// MarshalJSON encodes the tree as a nested jsonNode document, mainly
+// useful for debugging a tree's shape by eye; round-trip it with
+// UnmarshalJSON rather than hand-editing it.
+func (t Tree) MarshalJSON() ([]byte, error) {
+	return json.Marshal(nodeToJSON(t.root, t.specialEndRune))
+}
+
+// UnmarshalJSON replaces the tree's contents with the document
+// produced by MarshalJSON. The tree's specialEndRune is recovered
+// from the document's root rune, since the root node's val is always
+// the tree's specialEndRune.
+func (t *Tree) UnmarshalJSON(data []byte) error {
+	var jn jsonNode
+	if err := json.Unmarshal(data, &jn); err != nil {
+		return err
+	}
+
+	t.specialEndRune = jn.R
+	t.root = nodeFromJSON(jn, jn.R)
+	return nil
+}
+
+// nodeToJSON converts n into its jsonNode form, in sorted rune order.
+func nodeToJSON(n *trieNode, specialEndRune rune) jsonNode {
+	jn := jsonNode{R: n.val}
+
+	keys := make([]rune, 0, len(n.children))
+	for r := range n.children {
+		if r == specialEndRune {
+			jn.Word = true
+			continue
+		}
+
+		keys = append(keys, r)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	for _, r := range keys {
+		jn.Children = append(jn.Children, nodeToJSON(n.children[r], specialEndRune))
+	}
+
+	return jn
+}
+
+// nodeFromJSON is the inverse of nodeToJSON.
+func nodeFromJSON(jn jsonNode, specialEndRune rune) *trieNode {
+	n := newTrieNode(jn.R, true)
+	if jn.Word {
+		n.children[specialEndRune] = nil
+	}
+
+	for _, c := range jn.Children {
+		n.children[c.R] = nodeFromJSON(c, specialEndRune)
+	}
+
+	return n
+}
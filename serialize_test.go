@@ -0,0 +1,98 @@
+package trie
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	tt := NewTrieTree('*')
+	words := []string{"robert", "bad", "wold", "abcdefghijklmnopqrstuvwxyz"}
+	for _, word := range words {
+		tt.Add(word)
+	}
+
+	data, err := tt.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned an error: %v", err)
+	}
+
+	var got Tree
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary returned an error: %v", err)
+	}
+
+	for _, word := range words {
+		if !got.Contains(word) {
+			t.Errorf("Round-tripped tree should contain word %s", word)
+		}
+	}
+	if got.Contains("missing") {
+		t.Error("Round-tripped tree should not contain a word that was never added")
+	}
+	if !reflect.DeepEqual(got.AllWords(), tt.AllWords()) {
+		t.Errorf("Round-tripped tree words Expected: %v, Actual: %v", tt.AllWords(), got.AllWords())
+	}
+}
+
+func TestWriteToReadFrom(t *testing.T) {
+	tt := NewTrieTree('*')
+	for _, word := range []string{"golang", "gopher"} {
+		tt.Add(word)
+	}
+
+	var buf bytes.Buffer
+	n, err := tt.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo returned an error: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo Error: Expected to report %d bytes written, Actual: %d", buf.Len(), n)
+	}
+
+	var got Tree
+	if _, err := got.ReadFrom(&buf); err != nil {
+		t.Fatalf("ReadFrom returned an error: %v", err)
+	}
+
+	for _, word := range []string{"golang", "gopher"} {
+		if !got.Contains(word) {
+			t.Errorf("Tree read back from ReadFrom should contain word %s", word)
+		}
+	}
+}
+
+func TestUnmarshalBinaryRejectsBadMagic(t *testing.T) {
+	var got Tree
+	if err := got.UnmarshalBinary([]byte("not a trie")); err == nil {
+		t.Error("UnmarshalBinary should return an error for data with the wrong magic")
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	tt := NewTrieTree('*')
+	words := []string{"car", "cart", "care", "cat", "dog"}
+	for _, word := range words {
+		tt.Add(word)
+	}
+
+	data, err := tt.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON returned an error: %v", err)
+	}
+
+	var got Tree
+	if err := got.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON returned an error: %v", err)
+	}
+
+	for _, word := range words {
+		if !got.Contains(word) {
+			t.Errorf("Round-tripped tree should contain word %s", word)
+		}
+	}
+	if !reflect.DeepEqual(got.AllWords(), tt.AllWords()) {
+		t.Errorf("Round-tripped tree words Expected: %v, Actual: %v", tt.AllWords(), got.AllWords())
+	}
+}
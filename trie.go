@@ -5,6 +5,22 @@ type trieNode struct {
 	val      rune
 	clean    bool
 	children map[rune]*trieNode
+
+	// fail and dictLink are only populated once Compile has been
+	// run and are used by Tree.FindAll/Tree.Scan to walk the
+	// Aho-Corasick automaton built on top of the trie. depth is
+	// the number of runes from the root to this node.
+	fail     *trieNode
+	dictLink *trieNode
+	depth    int
+
+	// compiled and maxDepth are only meaningful on the root node:
+	// compiled tracks whether fail/dictLink are up to date with the
+	// tree's words, and maxDepth is the depth of the deepest node,
+	// i.e. the length of the longest word in the tree, which Scan
+	// uses to bound how much input it has to keep buffered.
+	compiled bool
+	maxDepth int
 }
 
 // Tree is a trie tree ADT that holds words of an alphabet.
@@ -16,14 +32,14 @@ type Tree struct {
 // newTrieNode creates a new trieNode with the given value and
 // the given clean bit set.
 func newTrieNode(val rune, clean bool) *trieNode {
-	return &trieNode{val, clean, make(map[rune]*trieNode)}
+	return &trieNode{val: val, clean: clean, children: make(map[rune]*trieNode)}
 }
 
 // NewTrieTree creates a new to a TrieTree that keeps the given
 // specialEndRune as a special character that cannot be used in
 // words given to the tree.
 func NewTrieTree(specialEndRune rune) *Tree {
-	return &Tree{newTrieNode(specialEndRune, true), specialEndRune}
+	return &Tree{root: newTrieNode(specialEndRune, true), specialEndRune: specialEndRune}
 }
 
 // Add adds the given word to the tree.
@@ -45,7 +61,7 @@ func (t Tree) Add(word string) bool {
 
 		// First, we need to see if the char is in the map
 		if _, ok := curNode.children[c]; !ok {
-			curNode.children[c] = &trieNode{c, false, make(map[rune]*trieNode)}
+			curNode.children[c] = &trieNode{val: c, clean: false, children: make(map[rune]*trieNode)}
 		}
 
 		// Next, set the curNode to the matching one in children
@@ -100,6 +116,10 @@ func (t Tree) Add(word string) bool {
 		curNode.clean = true
 	}
 
+	// The trie changed, so any previously compiled Aho-Corasick
+	// automaton is now stale.
+	t.root.compiled = false
+
 	return true
 }
 
@@ -125,20 +145,118 @@ func (t Tree) Contains(word string) bool {
 	return ok
 }
 
-// Delete removes the given word from the tree.
-func (t Tree) Delete(word string) {
+// Delete removes the given word from the tree and reports whether it
+// was actually present. Besides clearing the special end rune off
+// the last node, Delete walks back up the path it just took,
+// pruning any node whose children map is left empty and which isn't
+// itself a word terminator, so removing words from a large trie
+// doesn't leak the nodes that used to spell them.
+func (t Tree) Delete(word string) bool {
 	if len(word) == 0 {
-		return
+		return false
 	}
 
+	path := make([]*trieNode, 1, len(word)+1)
+	path[0] = t.root
+	runes := make([]rune, 0, len(word))
+
 	curNode := t.root
 	for _, c := range word {
-		if _, ok := curNode.children[c]; !ok {
-			break
+		next, ok := curNode.children[c]
+		if !ok || next == nil {
+			return false
 		}
 
-		curNode = curNode.children[c]
+		curNode = next
+		path = append(path, curNode)
+		runes = append(runes, c)
+	}
+
+	if _, ok := curNode.children[t.specialEndRune]; !ok {
+		return false
 	}
 
 	delete(curNode.children, t.specialEndRune)
+	pruneDeadPath(path, runes)
+
+	// The trie changed, so any previously compiled Aho-Corasick
+	// automaton is now stale.
+	t.root.compiled = false
+
+	return true
+}
+
+// DeleteSubtree removes prefix and everything under it, returning
+// the number of words that were removed. It is useful for pruning
+// a whole namespace out of a trie at once rather than calling
+// Delete word by word.
+func (t Tree) DeleteSubtree(prefix string) int {
+	path := make([]*trieNode, 1, len(prefix)+1)
+	path[0] = t.root
+	runes := make([]rune, 0, len(prefix))
+
+	curNode := t.root
+	for _, c := range prefix {
+		next, ok := curNode.children[c]
+		if !ok || next == nil {
+			return 0
+		}
+
+		curNode = next
+		path = append(path, curNode)
+		runes = append(runes, c)
+	}
+
+	count := t.countWords(curNode)
+	if count == 0 {
+		return 0
+	}
+
+	if len(path) == 1 {
+		// prefix is empty: clear everything under the root but
+		// keep the root node itself.
+		t.root.children = make(map[rune]*trieNode)
+	} else {
+		parent := path[len(path)-2]
+		delete(parent.children, runes[len(runes)-1])
+		pruneDeadPath(path[:len(path)-1], runes[:len(runes)-1])
+	}
+
+	t.root.compiled = false
+
+	return count
+}
+
+// countWords returns the number of words terminating at or under
+// node.
+func (t Tree) countWords(node *trieNode) int {
+	count := 0
+	if _, ok := node.children[t.specialEndRune]; ok {
+		count++
+	}
+
+	for r, child := range node.children {
+		if r == t.specialEndRune {
+			continue
+		}
+
+		count += t.countWords(child)
+	}
+
+	return count
+}
+
+// pruneDeadPath walks path (root first, deepest node last, paired
+// with the rune that reached each node after the root) from the
+// bottom up, removing any node left with no children. It stops as
+// soon as it reaches a node that still has children, since nodes
+// above that point are still needed by some other word.
+func pruneDeadPath(path []*trieNode, runes []rune) {
+	for i := len(path) - 1; i > 0; i-- {
+		if len(path[i].children) > 0 {
+			return
+		}
+
+		delete(path[i-1].children, runes[i-1])
+	}
 }